@@ -17,10 +17,13 @@ else
     REL=(-ldflags "-X main.appBuild=release")
 fi
 
+if ! command -v go >/dev/null 2>&1; then
+    echo "Go toolchain not found; add go to PATH"
+    exit 1
+fi
+
 PACKAGE=dcrinstall
 MAINDIR=$PACKAGE-$TAG
-mkdir -p $MAINDIR
-cd $MAINDIR
 
 SYS="windows-386 windows-amd64 openbsd-386 openbsd-amd64 linux-386 linux-amd64 linux-arm linux-arm64 darwin-amd64 freebsd-386 freebsd-amd64 freebsd-arm netbsd-386 netbsd-amd64"
 #BROKEN dragonfly-amd64 solaris-amd64
@@ -29,17 +32,35 @@ SYS="windows-386 windows-amd64 openbsd-386 openbsd-amd64 linux-386 linux-amd64 l
 # (something that is totally allowed).
 GPATH=$(echo $GOPATH | cut -f1 -d:)
 
+if ! go list github.com/decred/decred-release/cmd/dcrinstall >/dev/null; then
+    echo "Unable to find the packages to build in GOPATH:" $GOPATH
+    exit 1
+fi
+
+mkdir -p $MAINDIR
+cd $MAINDIR
+
+# Remove the manifest left by a previous run with the same tag.
+rm -f manifest-dcrinstall-$TAG.txt manifest-dcrinstall-$TAG.txt.tmp
+
 for i in $SYS; do
     OS=$(echo $i | cut -f1 -d-)
     ARCH=$(echo $i | cut -f2 -d-)
     echo "Building:" $OS $ARCH
     env GOOS=$OS GOARCH=$ARCH go build "${REL[@]}" github.com/decred/decred-release/cmd/dcrinstall
     if [[ $OS = "windows" ]]; then
-	mv dcrinstall.exe dcrinstall-$i-$TAG.exe
+	b=dcrinstall.exe
+	out=dcrinstall-$i-$TAG.exe
     else
-	mv dcrinstall dcrinstall-$i-$TAG
+	b=dcrinstall
+	out=dcrinstall-$i-$TAG
+    fi
+    if [ ! -e $b ]; then
+	echo "Missing binary:" $b "for" $OS $ARCH
+	exit 1
     fi
+    mv $b $out
 done
 
-sha256sum * > manifest-dcrinstall-$TAG.txt
+sha256sum * > manifest-dcrinstall-$TAG.txt.tmp && mv manifest-dcrinstall-$TAG.txt.tmp manifest-dcrinstall-$TAG.txt || { rm -f manifest-dcrinstall-$TAG.txt.tmp; exit 1; }
 
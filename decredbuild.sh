@@ -17,10 +17,13 @@ else
     REL=(-ldflags "-X main.appBuild=release")
 fi
 
+if ! command -v go >/dev/null 2>&1; then
+    echo "Go toolchain not found; add go to PATH"
+    exit 1
+fi
+
 PACKAGE=decred
 MAINDIR=$PACKAGE-$TAG
-mkdir -p $MAINDIR
-cd $MAINDIR
 
 SYS="windows-386 windows-amd64 openbsd-386 openbsd-amd64 linux-386 linux-amd64 linux-arm linux-arm64 darwin-amd64 dragonfly-amd64 freebsd-386 freebsd-amd64 freebsd-arm netbsd-386 netbsd-amd64 solaris-amd64"
 
@@ -28,9 +31,42 @@ SYS="windows-386 windows-amd64 openbsd-386 openbsd-amd64 linux-386 linux-amd64 l
 # (something that is totally allowed).
 GPATH=$(echo $GOPATH | cut -f1 -d:)
 
+# Binaries expected in each archive (with .exe added on windows).
+BINS="dcrd dcrctl promptsecret dcrwallet"
+
+# Extra files included in each archive alongside the binaries, relative to
+# $GPATH/src/github.com/decred.  A missing file is fatal so we never ship an
+# incomplete archive.
+EXTRA="dcrd/sample-dcrd.conf dcrd/cmd/dcrctl/sample-dcrctl.conf dcrwallet/sample-dcrwallet.conf"
+
+# Fail before creating anything if GOPATH can't provide what we build.
+if ! go list github.com/decred/dcrd github.com/decred/dcrd/cmd/dcrctl github.com/decred/dcrd/cmd/promptsecret github.com/decred/dcrwallet >/dev/null; then
+    echo "Unable to find the packages to build in GOPATH:" $GOPATH
+    exit 1
+fi
+for f in $EXTRA; do
+    if [ ! -e $GPATH/src/github.com/decred/$f ]; then
+	echo "Missing extra file:" $GPATH/src/github.com/decred/$f
+	exit 1
+    fi
+done
+
+mkdir -p $MAINDIR
+cd $MAINDIR
+
+# Remove checksum files left by a previous run with the same tag.
+rm -f manifest-$TAG.txt manifest-$TAG.txt.tmp manifest-$TAG.txt.sha256 SHA256SUMS
+
+# Remove any partially written archive, staging directory, or manifest if
+# we are interrupted so they can't be mistaken for complete ones.
+TOP=$(pwd)
+PARTIAL=""
+trap 'cd "$TOP"; rm -rf $PARTIAL manifest-$TAG.txt.tmp; exit 1' INT TERM
+
 for i in $SYS; do
     OS=$(echo $i | cut -f1 -d-)
     ARCH=$(echo $i | cut -f2 -d-)
+    PARTIAL="$PACKAGE-$i-$TAG $PACKAGE-$i-$TAG.zip $PACKAGE-$i-$TAG.tar.gz"
     mkdir $PACKAGE-$i-$TAG
     cd $PACKAGE-$i-$TAG
     echo "Building:" $OS $ARCH
@@ -38,9 +74,22 @@ for i in $SYS; do
     env GOOS=$OS GOARCH=$ARCH go build "${REL[@]}" github.com/decred/dcrd/cmd/dcrctl
     env GOOS=$OS GOARCH=$ARCH go build "${REL[@]}" github.com/decred/dcrd/cmd/promptsecret
     env GOOS=$OS GOARCH=$ARCH go build "${REL[@]}" github.com/decred/dcrwallet
-    cp $GPATH/src/github.com/decred/dcrd/sample-dcrd.conf .
-    cp $GPATH/src/github.com/decred/dcrd/cmd/dcrctl/sample-dcrctl.conf .
-    cp $GPATH/src/github.com/decred/dcrwallet/sample-dcrwallet.conf .
+    # A failed go build must not produce an archive with a tool silently
+    # missing from it.
+    for b in $BINS; do
+	if [[ $OS = "windows" ]]; then
+	    b=$b.exe
+	fi
+	if [ ! -e $b ]; then
+	    echo "Missing binary:" $b "for" $OS $ARCH
+	    cd "$TOP"
+	    rm -rf $PARTIAL
+	    exit 1
+	fi
+    done
+    for f in $EXTRA; do
+	cp $GPATH/src/github.com/decred/$f .
+    done
     cd ..
     if [[ $OS = "windows" ]]; then
 	zip -r $PACKAGE-$i-$TAG.zip $PACKAGE-$i-$TAG
@@ -49,6 +98,7 @@ for i in $SYS; do
 	tar -cvzf $PACKAGE-$i-$TAG.tar.gz $PACKAGE-$i-$TAG
     fi
     rm -r $PACKAGE-$i-$TAG
+    PARTIAL=""
 done
 
 if [ -e ../decred-copay-darwin-$TAG.dmg ]; then
@@ -61,4 +111,12 @@ if [ -e ../decred-copay-windows-$TAG.exe ]; then
     mv ../decred-copay-windows-$TAG.exe .
 fi
 
-sha256sum * > manifest-$TAG.txt
+# Write the manifest via a temp file so it is never left half written.
+sha256sum * > manifest-$TAG.txt.tmp && mv manifest-$TAG.txt.tmp manifest-$TAG.txt || { rm -f manifest-$TAG.txt.tmp; exit 1; }
+
+# Plain coreutils style checksums of just the archives for use with
+# sha256sum --check.
+sha256sum *.tar.gz *.zip > SHA256SUMS
+
+# Checksum of the manifest itself so mirrors can check it with one hash.
+sha256sum manifest-$TAG.txt > manifest-$TAG.txt.sha256
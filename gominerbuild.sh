@@ -20,28 +20,62 @@ else
     REL=(-ldflags "-X main.appBuild=release")
 fi
 
+if ! command -v go >/dev/null 2>&1; then
+    echo "Go toolchain not found; add go to PATH"
+    exit 1
+fi
+
 PACKAGE=gominer
 DATE=`date +%Y%m%d`
 MAINDIR=$PACKAGE-$TAG
+
+GPATH=$(echo $GOPATH | cut -f1 -d:)
+
+# Extra files for the archive, relative to the gominer source tree.
+EXTRA="sample-gominer.conf README.md LICENSE blake256.cl blake256-old.cl"
+
+if ! go list -tags opencl github.com/decred/gominer >/dev/null; then
+    echo "Unable to find the packages to build in GOPATH:" $GOPATH
+    exit 1
+fi
+for f in $EXTRA; do
+    if [ ! -e $GPATH/src/github.com/decred/gominer/$f ]; then
+	echo "Missing extra file:" $GPATH/src/github.com/decred/gominer/$f
+	exit 1
+    fi
+done
+
 mkdir -p $MAINDIR
 cd $MAINDIR
 
-GPATH=$(echo $GOPATH | cut -f1 -d:)
+# Remove the manifest left by a previous run with the same tag.
+rm -f manifest-gominer-$TAG.txt manifest-gominer-$TAG.txt.tmp
 
 OS=linux-amd64
 TYPE=opencl
+
+# Clean up the partial archive, staging directory, or manifest on interrupt.
+TOP=$(pwd)
+PARTIAL="$PACKAGE-$OS-$TYPE-$TAG $PACKAGE-$OS-$TYPE-$TAG.tar.gz"
+trap 'cd "$TOP"; rm -rf $PARTIAL manifest-gominer-$TAG.txt.tmp; exit 1' INT TERM
+
 echo Building $OS-$TYPE
 mkdir $PACKAGE-$OS-$TYPE-$TAG
 cd $PACKAGE-$OS-$TYPE-$TAG
 go build -tags "$TYPE" "${REL[@]}" github.com/decred/gominer
-cp $GPATH/src/github.com/decred/gominer/sample-gominer.conf .
-cp $GPATH/src/github.com/decred/gominer/README.md .
-cp $GPATH/src/github.com/decred/gominer/LICENSE .
-cp $GPATH/src/github.com/decred/gominer/blake256.cl .
-cp $GPATH/src/github.com/decred/gominer/blake256-old.cl .
+if [ ! -e gominer ]; then
+    echo "Missing binary: gominer for" $OS-$TYPE
+    cd "$TOP"
+    rm -rf $PARTIAL
+    exit 1
+fi
+for f in $EXTRA; do
+    cp $GPATH/src/github.com/decred/gominer/$f .
+done
 cd ..
 tar -czf $PACKAGE-$OS-$TYPE-$TAG.tar.gz $PACKAGE-$OS-$TYPE-$TAG
 rm -r $PACKAGE-$OS-$TYPE-$TAG
+PARTIAL=""
 
 SYS="windows-amd64_opencl_zip windows-amd64_cuda_zip linux-amd64_cuda_tar.gz windows-amd64_opencladl_zip linux-amd64_opencladl_tar.gz"
 
@@ -58,4 +92,4 @@ for i in $SYS; do
     fi
 done
 
-sha256sum * > manifest-gominer-$TAG.txt
+sha256sum * > manifest-gominer-$TAG.txt.tmp && mv manifest-gominer-$TAG.txt.tmp manifest-gominer-$TAG.txt || { rm -f manifest-gominer-$TAG.txt.tmp; exit 1; }